@@ -0,0 +1,932 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+	"gopkg.in/yaml.v2"
+)
+
+type JobStatusCommand struct {
+	Meta
+	length        int
+	evals         bool
+	verbose       bool
+	allAllocs     bool
+	short         bool
+	json          bool
+	yaml          bool
+	template      string
+	watch         bool
+	watchInterval time.Duration
+	watchUntil    string
+	filter        string
+	allocStatus   string
+	taskGroup     string
+	sortSpec      string
+}
+
+func (c *JobStatusCommand) Help() string {
+	helpText := `
+Usage: nomad job status [options] <job>
+
+  Display status information about a job. If no job ID is given, a list of
+  all known jobs will be displayed.
+
+  Upon successful job placement, this command will immediately return. It is
+  the callers responsibility to query further job information to verify the
+  scheduling result.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Status Options:
+
+  -all-allocs
+    Display all allocations matching the job ID, even those from an older
+    instance of the job.
+
+  -evals
+    Display the evaluations associated with the job.
+
+  -filter
+    Specifies an expression used to filter the allocations shown in the
+    Allocations table. The filter is executed server-side. See
+    https://www.nomadproject.io/api-docs/filtering for syntax.
+
+  -json
+    Output the job status in its JSON format.
+
+  -short
+    Display short output. Used only when a single job is being
+    queried, and drops verbose information about allocations.
+
+  -sort=<field>[:asc|desc]
+    Sort the Allocations table by the given field, e.g. "ModifyTime" or
+    "ModifyTime:desc". Applied client-side after any -filter/-status/
+    -task-group narrowing. Defaults to the order returned by the API.
+
+  -status=<comma-separated statuses>
+    Only show allocations whose client status is in the given list,
+    e.g. "running,failed". Combined with -filter and -task-group when
+    both are set.
+
+  -task-group=<name>
+    Only show allocations belonging to the given task group.
+
+  -t
+    Format and display the job status using a Go template.
+
+  -verbose
+    Display full information.
+
+  -watch
+    Continuously stream job status, re-rendering whenever the job,
+    its allocations, evaluations, or deployment change. Exits when the
+    job reaches a terminal status, when -watch-until is satisfied, or
+    on interrupt.
+
+  -watch-interval
+    The longest each blocking query waits for a change before the
+    status is checked again while watching. Defaults to 2s.
+
+  -watch-until=<status>
+    When set with -watch, stop watching and exit zero as soon as the
+    job's latest deployment reaches the given status (e.g. "successful"
+    or "failed").
+
+  -yaml
+    Output the job status in its YAML format.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobStatusCommand) Synopsis() string {
+	return "Display status information about a job"
+}
+
+func (c *JobStatusCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-all-allocs":     complete.PredictNothing,
+			"-evals":          complete.PredictNothing,
+			"-filter":         complete.PredictAnything,
+			"-json":           complete.PredictNothing,
+			"-short":          complete.PredictNothing,
+			"-sort":           complete.PredictAnything,
+			"-status":         complete.PredictAnything,
+			"-task-group":     complete.PredictAnything,
+			"-t":              complete.PredictAnything,
+			"-verbose":        complete.PredictNothing,
+			"-watch":          complete.PredictNothing,
+			"-watch-interval": complete.PredictAnything,
+			"-watch-until":    complete.PredictAnything,
+			"-yaml":           complete.PredictNothing,
+		})
+}
+
+func (c *JobStatusCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Jobs]
+	})
+}
+
+func (c *JobStatusCommand) Name() string { return "job status" }
+
+func (c *JobStatusCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&c.allAllocs, "all-allocs", false, "")
+	flags.BoolVar(&c.evals, "evals", false, "")
+	flags.BoolVar(&c.short, "short", false, "")
+	flags.BoolVar(&c.verbose, "verbose", false, "")
+	flags.BoolVar(&c.json, "json", false, "")
+	flags.BoolVar(&c.yaml, "yaml", false, "")
+	flags.StringVar(&c.template, "t", "", "")
+	flags.BoolVar(&c.watch, "watch", false, "")
+	flags.DurationVar(&c.watchInterval, "watch-interval", 2*time.Second, "")
+	flags.StringVar(&c.watchUntil, "watch-until", "", "")
+	flags.StringVar(&c.filter, "filter", "", "")
+	flags.StringVar(&c.allocStatus, "status", "", "")
+	flags.StringVar(&c.taskGroup, "task-group", "", "")
+	flags.StringVar(&c.sortSpec, "sort", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got either no jobs or exactly one.
+	args = flags.Args()
+	if len(args) > 1 {
+		c.Ui.Error("This command takes either no arguments or one: <job>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	c.length = shortId
+	if c.verbose {
+		c.length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Invoke list mode if no job ID was specified
+	if len(args) == 0 {
+		jobs, _, err := client.Jobs().List(nil)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error querying jobs: %s", err))
+			return 1
+		}
+
+		if len(jobs) == 0 {
+			// No output if we have no jobs
+			c.Ui.Output("No running jobs")
+		} else {
+			c.Ui.Output(createStatusListOutput(jobs, c.allNamespaces()))
+		}
+		return 0
+	}
+
+	// Try querying the job
+	jobID := args[0]
+	jobs, _, err := client.Jobs().PrefixList(jobID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying job: %s", err))
+		return 1
+	}
+	if len(jobs) == 0 {
+		c.Ui.Error(fmt.Sprintf("No job(s) with prefix or id %q found", jobID))
+		return 1
+	}
+	if len(jobs) > 1 && strings.TrimSpace(jobID) != jobs[0].ID {
+		out := make([]string, len(jobs)+1)
+		out[0] = "ID|Type|Priority|Status|Submit Date"
+		for i, job := range jobs {
+			out[i+1] = fmt.Sprintf("%s|%s|%d|%s|%s",
+				job.ID,
+				getTypeString(job),
+				job.Priority,
+				getStatusString(job.Status, job.Stop),
+				formatTime(time.Unix(0, job.SubmitTime)))
+		}
+		c.Ui.Output(fmt.Sprintf("Prefix matched multiple jobs\n\n%s", formatList(out)))
+		return 1
+	}
+	// Prefix lookup matched a single job
+	job, _, err := client.Jobs().Info(jobs[0].ID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying job: %s", err))
+		return 1
+	}
+
+	if c.watch {
+		return c.watchJob(client, *job.ID)
+	}
+
+	return c.renderJobStatus(client, job)
+}
+
+// renderJobStatus prints (or, in -json/-yaml/-t mode, formats) the full
+// status view for a single job: job info, summary, deployment(s), and
+// either allocations/evaluations or the periodic launch history.
+func (c *JobStatusCommand) renderJobStatus(client *api.Client, job *api.Job) int {
+	if c.json || len(c.template) > 0 {
+		data, err := c.gatherStatusData(client, job)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error gathering job status: %s", err))
+			return 1
+		}
+		out, err := Format(c.json, c.template, data)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	if c.yaml {
+		data, err := c.gatherStatusData(client, job)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error gathering job status: %s", err))
+			return 1
+		}
+		out, err := formatYaml(data)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	periodic := job.IsPeriodic()
+	parameterized := job.IsParameterized()
+
+	// Format the job info
+	basic := []string{
+		fmt.Sprintf("ID|%s", *job.ID),
+		fmt.Sprintf("Name|%s", *job.Name),
+		fmt.Sprintf("Submit Date|%s", formatTime(time.Unix(0, *job.SubmitTime))),
+		fmt.Sprintf("Type|%s", *job.Type),
+		fmt.Sprintf("Priority|%d", *job.Priority),
+		fmt.Sprintf("Datacenters|%s", strings.Join(job.Datacenters, ",")),
+		fmt.Sprintf("Namespace|%s", *job.Namespace),
+		fmt.Sprintf("Status|%s", getStatusString(*job.Status, job.Stop)),
+		fmt.Sprintf("Periodic|%v", periodic),
+		fmt.Sprintf("Parameterized|%v", parameterized),
+	}
+
+	c.Ui.Output(c.Colorize().Color(formatKV(basic)))
+
+	if !c.short {
+		c.outputJobSummary(client, job)
+	}
+
+	// Print periodic details, if we are a periodic job parent
+	if periodic && !parameterized {
+		if err := c.outputPeriodicInfo(client, job); err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+	}
+
+	// Print parameterized job details, if we are a parameterized job parent
+	if parameterized {
+		c.outputParameterizedInfo(job)
+	}
+
+	// Print the latest deployment if it exists
+	if err := c.outputLatestDeployment(client, job); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	// Print the multiregion deployment if it exists
+	if err := c.outputMultiregionDeployment(client, job); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	// Either show the periodic job launch or the latest allocations
+	if periodic && !parameterized {
+		if err := c.outputNextPeriodicLaunch(client, job); err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+	} else if !parameterized && !c.short {
+		if err := c.outputJobInfo(client, job); err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// watchIndices tracks the last-seen Nomad index for each of the
+// resources watchJob blocks on, so each round only re-renders once one
+// of them actually advances.
+type watchIndices struct {
+	job, alloc, eval, deploy uint64
+}
+
+// watchJob streams the job status view, blocking between renders on the
+// job's allocations, evaluations, and latest deployment (in addition to
+// the job itself) so it re-renders on any of their index bumps, similar
+// to `kubectl get -w`. It exits when the job reaches a terminal status,
+// when -watch-until matches the latest deployment's status, or on
+// SIGINT.
+func (c *JobStatusCommand) watchJob(client *api.Client, jobID string) int {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	defer signal.Stop(sigCh)
+
+	var idx watchIndices
+	first := true
+	for {
+		job, deploy, changed, interrupted, err := c.pollWatchIndices(client, jobID, &idx, sigCh)
+		if interrupted {
+			return 0
+		}
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error querying job: %s", err))
+			return 1
+		}
+
+		if changed {
+			if !first {
+				c.clearScreen()
+			}
+			if code := c.renderJobStatus(client, job); code != 0 {
+				return code
+			}
+		}
+		first = false
+
+		terminal := isJobStatusTerminal(*job.Status)
+		if c.watchUntil != "" {
+			if deploy != nil && strings.EqualFold(deploy.Status, c.watchUntil) {
+				return 0
+			}
+			if terminal {
+				c.Ui.Error(fmt.Sprintf("Job reached terminal status %q before its deployment reached %q", *job.Status, c.watchUntil))
+				return 1
+			}
+		} else if terminal {
+			return 0
+		}
+	}
+}
+
+// pollWatchIndices blocks (via QueryOptions.WaitIndex/WaitTime, capped
+// at -watch-interval) on the job, its allocations, its evaluations, and
+// its latest deployment concurrently, so a change to any one of them —
+// not just the job's own ModifyIndex — is picked up. The round ends only
+// once all four queries have returned, so a change to one resource is
+// seen when the others have also changed or reached their wait time. It
+// reports whether any tracked index advanced, and returns promptly with
+// interrupted=true if sigCh fires before the round completes.
+func (c *JobStatusCommand) pollWatchIndices(client *api.Client, jobID string, idx *watchIndices, sigCh <-chan os.Signal) (job *api.Job, deploy *api.Deployment, changed bool, interrupted bool, err error) {
+	waitTime := c.watchInterval
+	if waitTime <= 0 {
+		waitTime = 2 * time.Second
+	}
+
+	var (
+		wg                                   sync.WaitGroup
+		jobIdx, allocIdx, evalIdx, deployIdx uint64
+		jobErr, allocErr, evalErr, deployErr error
+	)
+
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		var meta *api.QueryMeta
+		job, meta, jobErr = client.Jobs().Info(jobID, &api.QueryOptions{WaitIndex: idx.job, WaitTime: waitTime})
+		if meta != nil {
+			jobIdx = meta.LastIndex
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		_, meta, e := client.Jobs().Allocations(jobID, c.allAllocs, &api.QueryOptions{WaitIndex: idx.alloc, WaitTime: waitTime, Filter: c.buildAllocFilter()})
+		allocErr = e
+		if meta != nil {
+			allocIdx = meta.LastIndex
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		_, meta, e := client.Jobs().Evaluations(jobID, &api.QueryOptions{WaitIndex: idx.eval, WaitTime: waitTime})
+		evalErr = e
+		if meta != nil {
+			evalIdx = meta.LastIndex
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		var meta *api.QueryMeta
+		deploy, meta, deployErr = client.Jobs().LatestDeployment(jobID, &api.QueryOptions{WaitIndex: idx.deploy, WaitTime: waitTime})
+		if meta != nil {
+			deployIdx = meta.LastIndex
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-sigCh:
+		return nil, nil, false, true, nil
+	case <-done:
+	}
+
+	for _, e := range []error{jobErr, allocErr, evalErr, deployErr} {
+		if e != nil {
+			return nil, nil, false, false, e
+		}
+	}
+
+	changed = jobIdx > idx.job || allocIdx > idx.alloc || evalIdx > idx.eval || deployIdx > idx.deploy
+	idx.job, idx.alloc, idx.eval, idx.deploy = jobIdx, allocIdx, evalIdx, deployIdx
+	return job, deploy, changed, false, nil
+}
+
+// clearScreen resets the terminal so each watch render replaces the
+// previous one in place, rather than scrolling.
+func (c *JobStatusCommand) clearScreen() {
+	c.Ui.Output("\033[H\033[2J")
+}
+
+// isJobStatusTerminal reports whether a job has reached a status that
+// will never produce further status changes on its own.
+func isJobStatusTerminal(status string) bool {
+	return status == "dead"
+}
+
+// outputJobSummary displays the job summary as a table of per task group
+// allocation counts.
+func (c *JobStatusCommand) outputJobSummary(client *api.Client, job *api.Job) {
+	// Query the summary
+	summary, _, err := client.Jobs().Summary(*job.ID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying job summary: %s", err))
+		return
+	}
+
+	if summary == nil {
+		return
+	}
+
+	// Print the summary
+	c.Ui.Output(c.Colorize().Color("\n[bold]Summary[reset]"))
+	if len(summary.Summary) == 0 {
+		c.Ui.Output("Task Group Summary unavailable")
+		return
+	}
+
+	taskGroups := make([]string, len(summary.Summary)+1)
+	taskGroups[0] = "Task Group|Queued|Starting|Running|Failed|Complete|Lost"
+	taskGroupNames := make([]string, 0, len(summary.Summary))
+	for taskGroup := range summary.Summary {
+		taskGroupNames = append(taskGroupNames, taskGroup)
+	}
+	sort.Strings(taskGroupNames)
+	for idx, taskGroupName := range taskGroupNames {
+		tgs := summary.Summary[taskGroupName]
+		taskGroups[idx+1] = fmt.Sprintf("%s|%d|%d|%d|%d|%d|%d",
+			taskGroupName, tgs.Queued, tgs.Starting,
+			tgs.Running, tgs.Failed, tgs.Complete, tgs.Lost)
+	}
+	c.Ui.Output(formatList(taskGroups))
+}
+
+func (c *JobStatusCommand) outputPeriodicInfo(client *api.Client, job *api.Job) error {
+	// Output the periodic details
+	c.Ui.Output(c.Colorize().Color("\n[bold]Periodic Details[reset]"))
+	next := api.NextPeriodicLaunch(job, time.Now())
+	basic := []string{
+		fmt.Sprintf("Next Periodic Launch|%s", next),
+	}
+	c.Ui.Output(formatKV(basic))
+	return nil
+}
+
+func (c *JobStatusCommand) outputParameterizedInfo(job *api.Job) {
+	// Output parameterized job details
+	c.Ui.Output(c.Colorize().Color("\n[bold]Parameterized Job[reset]"))
+	parameterizedJob := job.ParameterizedJob
+	if parameterizedJob == nil {
+		return
+	}
+
+	basic := []string{
+		fmt.Sprintf("Payload|%s", parameterizedJob.Payload),
+		fmt.Sprintf("Required Metadata|%s", strings.Join(parameterizedJob.MetaRequired, ",")),
+		fmt.Sprintf("Optional Metadata|%s", strings.Join(parameterizedJob.MetaOptional, ",")),
+	}
+	c.Ui.Output(formatKV(basic))
+}
+
+func (c *JobStatusCommand) outputNextPeriodicLaunch(client *api.Client, job *api.Job) error {
+	// Generate the periodic job's launch
+	children, _, err := client.Jobs().PrefixList(*job.ID)
+	if err != nil {
+		return fmt.Errorf("Error querying job: %s", err)
+	}
+
+	if len(children) == 0 {
+		c.Ui.Output("\nNo instances of periodic job found")
+		return nil
+	}
+
+	out := make([]string, 1)
+	out[0] = "ID|Status"
+	for _, child := range children {
+		if child.ParentID != *job.ID {
+			continue
+		}
+		out = append(out, fmt.Sprintf("%s|%s", child.ID, getStatusString(child.Status, child.Stop)))
+	}
+	c.Ui.Output(c.Colorize().Color("\n[bold]Previously Launched Jobs[reset]"))
+	c.Ui.Output(formatList(out))
+	return nil
+}
+
+func (c *JobStatusCommand) outputLatestDeployment(client *api.Client, job *api.Job) error {
+	deploy, _, err := client.Jobs().LatestDeployment(*job.ID, nil)
+	if err != nil {
+		return fmt.Errorf("Error querying latest job deployment: %s", err)
+	}
+
+	if deploy == nil {
+		return nil
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Latest Deployment[reset]"))
+	basic := []string{
+		fmt.Sprintf("ID|%s", limit(deploy.ID, c.length)),
+		fmt.Sprintf("Status|%s", deploy.Status),
+		fmt.Sprintf("Description|%s", deploy.StatusDescription),
+	}
+	c.Ui.Output(formatKV(basic))
+	return nil
+}
+
+func (c *JobStatusCommand) outputMultiregionDeployment(client *api.Client, job *api.Job) error {
+	regionDeploys, err := c.gatherMultiregionDeployments(client, job)
+	if err != nil {
+		return err
+	}
+	if len(regionDeploys) == 0 {
+		return nil
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Multiregion Deployment[reset]"))
+	rows := make([]string, len(regionDeploys)+1)
+	rows[0] = "Region|ID|Status"
+	for i, rd := range regionDeploys {
+		rows[i+1] = fmt.Sprintf("%s|%s|%s", rd.Region, limit(rd.ID, c.length), rd.Status)
+	}
+	c.Ui.Output(formatList(rows))
+	return nil
+}
+
+// regionDeployment pairs a deployment with the region it was queried
+// from, since api.Deployment doesn't carry its own region.
+type regionDeployment struct {
+	Region string
+	*api.Deployment
+}
+
+// gatherMultiregionDeployments fetches the latest deployment from each
+// region a multiregion job targets. A single Deployments call only
+// returns deployments local to the region it's issued against, so each
+// region is queried individually and the results are merged.
+func (c *JobStatusCommand) gatherMultiregionDeployments(client *api.Client, job *api.Job) ([]*regionDeployment, error) {
+	if job.Multiregion == nil || len(job.Multiregion.Regions) < 2 {
+		return nil, nil
+	}
+
+	var regionDeploys []*regionDeployment
+	for _, region := range job.Multiregion.Regions {
+		deploys, _, err := client.Jobs().Deployments(*job.ID, true, &api.QueryOptions{Region: region.Name})
+		if err != nil {
+			return nil, fmt.Errorf("Error querying job deployments in region %q: %s", region.Name, err)
+		}
+		if len(deploys) == 0 {
+			continue
+		}
+		regionDeploys = append(regionDeploys, &regionDeployment{Region: region.Name, Deployment: deploys[0]})
+	}
+	return regionDeploys, nil
+}
+
+func (c *JobStatusCommand) outputJobInfo(client *api.Client, job *api.Job) error {
+	var evals, allocs []string
+
+	// Query the allocations, pushing any -filter/-status/-task-group
+	// narrowing to the server.
+	jobAllocs, _, err := client.Jobs().Allocations(*job.ID, c.allAllocs, &api.QueryOptions{Filter: c.buildAllocFilter()})
+	if err != nil {
+		return fmt.Errorf("Error querying job allocations: %s", err)
+	}
+	if err := sortAllocations(jobAllocs, c.sortSpec); err != nil {
+		return err
+	}
+
+	// Query the evaluations
+	jobEvals, _, err := client.Jobs().Evaluations(*job.ID, nil)
+	if err != nil {
+		return fmt.Errorf("Error querying job evaluations: %s", err)
+	}
+
+	// Determine pending reschedule attempts by following each
+	// allocation's FollowupEvalID, to show in the Future Rescheduling
+	// Attempts section.
+	var reschedEvals []*api.Evaluation
+	seenFollowup := make(map[string]bool)
+	for _, alloc := range jobAllocs {
+		if alloc.FollowupEvalID == "" || seenFollowup[alloc.FollowupEvalID] {
+			continue
+		}
+		seenFollowup[alloc.FollowupEvalID] = true
+
+		followupEval, _, err := client.Evaluations().Info(alloc.FollowupEvalID, nil)
+		if err != nil {
+			return fmt.Errorf("Error querying follow up evaluation: %s", err)
+		}
+		if followupEval.WaitUntil.IsZero() || time.Now().After(followupEval.WaitUntil) {
+			continue
+		}
+		reschedEvals = append(reschedEvals, followupEval)
+	}
+
+	// Format the evals
+	evals = make([]string, len(jobEvals)+1)
+	evals[0] = "ID|Priority|Triggered By|Status|Placement Failures"
+	for i, eval := range jobEvals {
+		failures, _ := evalFailureStatus(eval)
+		evals[i+1] = fmt.Sprintf("%s|%d|%s|%s|%s",
+			limit(eval.ID, c.length),
+			eval.Priority,
+			eval.TriggeredBy,
+			eval.Status,
+			failures)
+	}
+
+	if c.verbose || c.evals {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Evaluations[reset]"))
+		c.Ui.Output(formatList(evals))
+	}
+
+	if len(reschedEvals) > 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Future Rescheduling Attempts[reset]"))
+		rows := make([]string, len(reschedEvals)+1)
+		rows[0] = "Eval ID|Reschedule Time"
+		for i, eval := range reschedEvals {
+			rows[i+1] = fmt.Sprintf("%s|%s", limit(eval.ID, c.length), formatTime(eval.WaitUntil))
+		}
+		c.Ui.Output(formatList(rows))
+	}
+
+	// Format the allocs
+	if c.verbose {
+		allocs = make([]string, len(jobAllocs)+1)
+		allocs[0] = "ID|Eval ID|Node ID|Node Name|Task Group|Version|Desired|Status|Created|Modified"
+	} else {
+		allocs = make([]string, len(jobAllocs)+1)
+		allocs[0] = "ID|Node ID|Task Group|Version|Desired|Status|Created|Modified"
+	}
+
+	for i, alloc := range jobAllocs {
+		now := time.Now()
+		createTimePretty := prettyTimeDiff(time.Unix(0, alloc.CreateTime), now)
+		modTimePretty := prettyTimeDiff(time.Unix(0, alloc.ModifyTime), now)
+		if c.verbose {
+			allocs[i+1] = fmt.Sprintf("%s|%s|%s|%s|%d|%d|%s|%s|%s|%s",
+				limit(alloc.ID, c.length),
+				limit(alloc.EvalID, c.length),
+				limit(alloc.NodeID, c.length),
+				alloc.NodeName,
+				alloc.TaskGroup,
+				alloc.JobVersion,
+				alloc.DesiredStatus,
+				alloc.ClientStatus,
+				createTimePretty,
+				modTimePretty)
+		} else {
+			allocs[i+1] = fmt.Sprintf("%s|%s|%s|%d|%s|%s|%s|%s",
+				limit(alloc.ID, c.length),
+				limit(alloc.NodeID, c.length),
+				alloc.TaskGroup,
+				alloc.JobVersion,
+				alloc.DesiredStatus,
+				alloc.ClientStatus,
+				createTimePretty,
+				modTimePretty)
+		}
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Allocations[reset]"))
+	c.Ui.Output(formatList(allocs))
+	return nil
+}
+
+// allNamespaces checks for the job -namespace flag or '-all' namespace value
+func (c *JobStatusCommand) allNamespaces() bool {
+	return c.Meta.namespace == "*"
+}
+
+// jobStatusData is the aggregate view of a job's status used to render the
+// structured (-json/-yaml/-t) output modes. It holds the job, its summary,
+// allocations, evaluations, latest deployment, multiregion deployments and
+// pending reschedule evaluations, whatever -short, -evals or -verbose say.
+// The periodic and parameterized job sections of the table output are not
+// included, and MultiregionDeployments does not record the region of each
+// deployment.
+type jobStatusData struct {
+	Job                    *api.Job                  `json:"Job"`
+	Summary                *api.JobSummary           `json:"Summary,omitempty"`
+	Allocations            []*api.AllocationListStub `json:"Allocations,omitempty"`
+	Evaluations            []*api.Evaluation         `json:"Evaluations,omitempty"`
+	LatestDeployment       *api.Deployment           `json:"LatestDeployment,omitempty"`
+	MultiregionDeployments []*api.Deployment         `json:"MultiregionDeployments,omitempty"`
+	FutureReschedules      []*api.Evaluation         `json:"FutureReschedules,omitempty"`
+}
+
+// gatherStatusData collects the job's status into a single jobStatusData
+// document for -json/-yaml/-t output.
+func (c *JobStatusCommand) gatherStatusData(client *api.Client, job *api.Job) (*jobStatusData, error) {
+	data := &jobStatusData{Job: job}
+
+	summary, _, err := client.Jobs().Summary(*job.ID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying job summary: %s", err)
+	}
+	data.Summary = summary
+
+	allocs, _, err := client.Jobs().Allocations(*job.ID, c.allAllocs, &api.QueryOptions{Filter: c.buildAllocFilter()})
+	if err != nil {
+		return nil, fmt.Errorf("Error querying job allocations: %s", err)
+	}
+	if err := sortAllocations(allocs, c.sortSpec); err != nil {
+		return nil, err
+	}
+	data.Allocations = allocs
+
+	evals, _, err := client.Jobs().Evaluations(*job.ID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying job evaluations: %s", err)
+	}
+	data.Evaluations = evals
+
+	seenFollowup := make(map[string]bool)
+	for _, alloc := range allocs {
+		if alloc.FollowupEvalID == "" || seenFollowup[alloc.FollowupEvalID] {
+			continue
+		}
+		seenFollowup[alloc.FollowupEvalID] = true
+
+		followupEval, _, err := client.Evaluations().Info(alloc.FollowupEvalID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("Error querying follow up evaluation: %s", err)
+		}
+		if followupEval.WaitUntil.IsZero() || time.Now().After(followupEval.WaitUntil) {
+			continue
+		}
+		data.FutureReschedules = append(data.FutureReschedules, followupEval)
+	}
+
+	deploy, _, err := client.Jobs().LatestDeployment(*job.ID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying latest job deployment: %s", err)
+	}
+	data.LatestDeployment = deploy
+
+	regionDeploys, err := c.gatherMultiregionDeployments(client, job)
+	if err != nil {
+		return nil, err
+	}
+	for _, rd := range regionDeploys {
+		data.MultiregionDeployments = append(data.MultiregionDeployments, rd.Deployment)
+	}
+
+	return data, nil
+}
+
+// buildAllocFilter composes the server-side filter expression sent to
+// the Allocations endpoint from -filter, -status, and -task-group.
+func (c *JobStatusCommand) buildAllocFilter() string {
+	var parts []string
+	if c.filter != "" {
+		parts = append(parts, fmt.Sprintf("(%s)", c.filter))
+	}
+	if c.allocStatus != "" {
+		statuses := strings.Split(c.allocStatus, ",")
+		clauses := make([]string, len(statuses))
+		for i, status := range statuses {
+			clauses[i] = fmt.Sprintf("ClientStatus == %q", strings.TrimSpace(status))
+		}
+		parts = append(parts, fmt.Sprintf("(%s)", strings.Join(clauses, " or ")))
+	}
+	if c.taskGroup != "" {
+		parts = append(parts, fmt.Sprintf("TaskGroup == %q", c.taskGroup))
+	}
+	return strings.Join(parts, " and ")
+}
+
+// sortAllocations sorts allocs in place per a "-sort=<field>[:asc|desc]"
+// spec, used as the client-side fallback for fields the server-side
+// filter language can't express as ordering. A blank spec is a no-op.
+func sortAllocations(allocs []*api.AllocationListStub, spec string) error {
+	if spec == "" {
+		return nil
+	}
+
+	field, desc := spec, false
+	if idx := strings.LastIndex(spec, ":"); idx != -1 {
+		field = spec[:idx]
+		switch dir := spec[idx+1:]; dir {
+		case "asc":
+			desc = false
+		case "desc":
+			desc = true
+		default:
+			return fmt.Errorf("invalid -sort direction %q, must be \"asc\" or \"desc\"", dir)
+		}
+	}
+
+	less, err := allocSortLess(field)
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(allocs, func(i, j int) bool {
+		if desc {
+			return less(allocs[j], allocs[i])
+		}
+		return less(allocs[i], allocs[j])
+	})
+	return nil
+}
+
+// allocSortLess returns the less-than comparator for a -sort field name.
+func allocSortLess(field string) (func(a, b *api.AllocationListStub) bool, error) {
+	switch field {
+	case "ID":
+		return func(a, b *api.AllocationListStub) bool { return a.ID < b.ID }, nil
+	case "Name":
+		return func(a, b *api.AllocationListStub) bool { return a.Name < b.Name }, nil
+	case "TaskGroup":
+		return func(a, b *api.AllocationListStub) bool { return a.TaskGroup < b.TaskGroup }, nil
+	case "Status", "ClientStatus":
+		return func(a, b *api.AllocationListStub) bool { return a.ClientStatus < b.ClientStatus }, nil
+	case "CreateTime":
+		return func(a, b *api.AllocationListStub) bool { return a.CreateTime < b.CreateTime }, nil
+	case "ModifyTime":
+		return func(a, b *api.AllocationListStub) bool { return a.ModifyTime < b.ModifyTime }, nil
+	default:
+		return nil, fmt.Errorf("unsupported -sort field %q", field)
+	}
+}
+
+// formatYaml renders data (a *jobStatusData) as YAML for the -yaml
+// output mode. It holds the same data as the -json document, but yaml.v2
+// does not read json tags: keys are the lower-cased Go field names and
+// empty fields are not omitted.
+func formatYaml(data interface{}) (string, error) {
+	out, err := yaml.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("Error marshaling data to YAML: %s", err)
+	}
+	return string(out), nil
+}
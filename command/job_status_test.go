@@ -1,6 +1,7 @@
 package command
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
@@ -16,6 +17,7 @@ import (
 	"github.com/posener/complete"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 )
 
 func TestJobStatusCommand_Implements(t *testing.T) {
@@ -486,6 +488,127 @@ func TestJobStatusCommand_Multiregion(t *testing.T) {
 	require.NotContains(t, out, "<none>")
 
 }
+func TestJobStatusCommand_StructuredOutput(t *testing.T) {
+	t.Parallel()
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+	testutil.WaitForResult(func() (bool, error) {
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return false, err
+		}
+		if len(nodes) == 0 {
+			return false, fmt.Errorf("missing node")
+		}
+		if _, ok := nodes[0].Drivers["mock_driver"]; !ok {
+			return false, fmt.Errorf("mock_driver not ready")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	ui := new(cli.MockUi)
+	cmd := &JobStatusCommand{Meta: Meta{Ui: ui}}
+
+	job := testJob("job_status_json")
+	resp, _, err := client.Jobs().Register(job, nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, waitForSuccess(ui, client, fullId, t, resp.EvalID))
+	ui.OutputWriter.Reset()
+
+	// -json emits a single machine-checkable document containing the
+	// job, summary and allocations, instead of table output.
+	if code := cmd.Run([]string{"-address=" + url, "-json", "job_status_json"}); code != 0 {
+		t.Fatalf("expected exit 0, got: %d", code)
+	}
+	out := ui.OutputWriter.String()
+
+	var data struct {
+		Job struct {
+			ID string
+		}
+		Summary     map[string]interface{}
+		Allocations []interface{}
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &data))
+	require.Equal(t, "job_status_json", data.Job.ID)
+	require.NotNil(t, data.Summary)
+	ui.OutputWriter.Reset()
+
+	// -yaml emits the same data, YAML-encoded.
+	if code := cmd.Run([]string{"-address=" + url, "-yaml", "job_status_json"}); code != 0 {
+		t.Fatalf("expected exit 0, got: %d", code)
+	}
+	out = ui.OutputWriter.String()
+
+	var yamlData map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(out), &yamlData))
+	require.Contains(t, out, "job_status_json")
+	ui.OutputWriter.Reset()
+
+	// -t formats the same document through a Go template.
+	if code := cmd.Run([]string{"-address=" + url, "-t", "{{.Job.ID}}", "job_status_json"}); code != 0 {
+		t.Fatalf("expected exit 0, got: %d", code)
+	}
+	require.Equal(t, "job_status_json", strings.TrimSpace(ui.OutputWriter.String()))
+}
+
+func TestJobStatusCommand_IsJobStatusTerminal(t *testing.T) {
+	t.Parallel()
+	assert.True(t, isJobStatusTerminal("dead"))
+	assert.False(t, isJobStatusTerminal("running"))
+	assert.False(t, isJobStatusTerminal("pending"))
+}
+
+func TestJobStatusCommand_BuildAllocFilter(t *testing.T) {
+	t.Parallel()
+
+	cmd := &JobStatusCommand{}
+	require.Equal(t, "", cmd.buildAllocFilter())
+
+	cmd = &JobStatusCommand{allocStatus: "running,failed"}
+	require.Equal(t, `(ClientStatus == "running" or ClientStatus == "failed")`, cmd.buildAllocFilter())
+
+	cmd = &JobStatusCommand{taskGroup: "web"}
+	require.Equal(t, `TaskGroup == "web"`, cmd.buildAllocFilter())
+
+	cmd = &JobStatusCommand{filter: `NodeName == "n1"`, allocStatus: "running", taskGroup: "web"}
+	require.Equal(t,
+		`(NodeName == "n1") and (ClientStatus == "running") and TaskGroup == "web"`,
+		cmd.buildAllocFilter())
+}
+
+func TestJobStatusCommand_SortAllocations(t *testing.T) {
+	t.Parallel()
+
+	allocs := []*api.AllocationListStub{
+		{ID: "c", ModifyTime: 30},
+		{ID: "a", ModifyTime: 10},
+		{ID: "b", ModifyTime: 20},
+	}
+
+	require.NoError(t, sortAllocations(allocs, "ModifyTime"))
+	require.Equal(t, []string{"a", "b", "c"}, allocIDs(allocs))
+
+	require.NoError(t, sortAllocations(allocs, "ModifyTime:desc"))
+	require.Equal(t, []string{"c", "b", "a"}, allocIDs(allocs))
+
+	require.NoError(t, sortAllocations(allocs, ""))
+	require.Equal(t, []string{"c", "b", "a"}, allocIDs(allocs), "blank spec is a no-op")
+
+	require.Error(t, sortAllocations(allocs, "NotAField"))
+	require.Error(t, sortAllocations(allocs, "ModifyTime:sideways"))
+}
+
+func allocIDs(allocs []*api.AllocationListStub) []string {
+	ids := make([]string, len(allocs))
+	for i, a := range allocs {
+		ids[i] = a.ID
+	}
+	return ids
+}
+
 func waitForSuccess(ui cli.Ui, client *api.Client, length int, t *testing.T, evalId string) int {
 	mon := newMonitor(ui, client, length)
 	monErr := mon.monitor(evalId, false)